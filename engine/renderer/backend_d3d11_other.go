@@ -0,0 +1,11 @@
+//go:build !windows
+
+package renderer
+
+import "github.com/spaghettifunk/alaska-engine/engine/platform"
+
+// DirectX 11 is only available on Windows builds, so on every other
+// platform it reports as unavailable rather than being compiled in.
+func newD3D11Backend(plat *platform.Platform) RendererBackend {
+	return nil
+}
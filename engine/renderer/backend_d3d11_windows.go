@@ -0,0 +1,12 @@
+//go:build windows
+
+package renderer
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/platform"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/d3d11"
+)
+
+func newD3D11Backend(plat *platform.Platform) RendererBackend {
+	return d3d11.New(plat)
+}
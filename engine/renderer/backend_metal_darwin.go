@@ -0,0 +1,12 @@
+//go:build darwin
+
+package renderer
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/platform"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metal"
+)
+
+func newMetalBackend(plat *platform.Platform) RendererBackend {
+	return metal.New(plat)
+}
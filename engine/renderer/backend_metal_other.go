@@ -0,0 +1,11 @@
+//go:build !darwin
+
+package renderer
+
+import "github.com/spaghettifunk/alaska-engine/engine/platform"
+
+// Metal is only available on Darwin builds, so on every other platform it
+// reports as unavailable rather than being compiled in.
+func newMetalBackend(plat *platform.Platform) RendererBackend {
+	return nil
+}
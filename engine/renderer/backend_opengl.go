@@ -0,0 +1,13 @@
+package renderer
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/platform"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/opengl"
+)
+
+// newOpenGLBackend constructs the OpenGL backend. Desktop GL is available
+// on every platform this engine targets, so like Vulkan it needs no
+// "_other.go" stub.
+func newOpenGLBackend(plat *platform.Platform) RendererBackend {
+	return opengl.New(plat)
+}
@@ -0,0 +1,14 @@
+package renderer
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/platform"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/vulkan"
+)
+
+// newVulkanBackend constructs the Vulkan backend. Vulkan (via MoltenVK on
+// macOS) is available on every platform this engine targets, so like the
+// OpenGL backend (and unlike DirectX and Metal) it does not need an
+// "_other.go" stub.
+func newVulkanBackend(plat *platform.Platform) RendererBackend {
+	return vulkan.New(plat)
+}
@@ -0,0 +1,127 @@
+package batch
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/core"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+)
+
+// transformStride is the size in bytes of one instance's transform in the
+// SSBO: a single 4x4 float32 matrix.
+const transformStride uint64 = 4 * 4 * 4
+
+// indexStride is the size in bytes of one index in the index ring buffer;
+// this renderer always uses 32-bit indices.
+const indexStride uint64 = 4
+
+// vertexStride is the size in bytes of one vertex in the vertex ring
+// buffer: position (vec3) + normal (vec3) + uv (vec2), all float32.
+const vertexStride uint64 = (3 + 3 + 2) * 4
+
+// Batcher groups GeometryRenderData by pipeline/material so it can be
+// submitted as a single vkCmdDrawIndexedIndirect call instead of one draw
+// per geometry. Per-instance transforms are written into an SSBO-backed
+// ring buffer rather than a uniform buffer per draw, so scenes with
+// thousands of meshes still issue single-digit draw calls.
+type Batcher struct {
+	vertexRing    *RingBuffer
+	indexRing     *RingBuffer
+	transformRing *RingBuffer
+	indirectRing  *RingBuffer
+
+	// batches groups submitted geometry by pipeline/material key; cleared
+	// every BeginFrame.
+	batches map[string][]metadata.GeometryRenderData
+}
+
+func NewBatcher(framesInFlight int, vertexBufferSize, indexBufferSize, transformBufferSize, indirectBufferSize uint64) *Batcher {
+	return &Batcher{
+		vertexRing:    NewRingBuffer(metadata.RENDERBUFFER_TYPE_VERTEX, vertexBufferSize, framesInFlight),
+		indexRing:     NewRingBuffer(metadata.RENDERBUFFER_TYPE_INDEX, indexBufferSize, framesInFlight),
+		transformRing: NewRingBuffer(metadata.RENDERBUFFER_TYPE_STORAGE, transformBufferSize, framesInFlight),
+		indirectRing:  NewRingBuffer(metadata.RENDERBUFFER_TYPE_STORAGE, indirectBufferSize, framesInFlight),
+		batches:       make(map[string][]metadata.GeometryRenderData),
+	}
+}
+
+// BeginFrame advances every ring buffer to this frame's backing buffer and
+// discards any batches left over from the previous frame.
+func (b *Batcher) BeginFrame() {
+	b.vertexRing.Advance()
+	b.indexRing.Advance()
+	b.transformRing.Advance()
+	b.indirectRing.Advance()
+	for key := range b.batches {
+		delete(b.batches, key)
+	}
+}
+
+// Add appends geo to the batch for pipelineKey (typically the
+// material/pipeline's identity), so it is drawn as part of that
+// pipeline's single indirect draw this frame.
+func (b *Batcher) Add(pipelineKey string, geo metadata.GeometryRenderData) {
+	b.batches[pipelineKey] = append(b.batches[pipelineKey], geo)
+}
+
+// Build reserves a distinct range per pipeline from the transform, vertex
+// and index ring buffers' freelists (so two pipelines never alias the same
+// offsets) and returns one DrawIndirectCommand per pipeline, ready to be
+// recorded with vkCmdDrawIndexedIndirect. It only reserves the ranges: no
+// transform, vertex or index bytes are copied into them.
+func (b *Batcher) Build() map[string]metadata.DrawIndirectCommand {
+	transforms := b.transformRing.Current()
+	vertices := b.vertexRing.Current()
+	indices := b.indexRing.Current()
+	commands := make(map[string]metadata.DrawIndirectCommand, len(b.batches))
+	for pipelineKey, geometries := range b.batches {
+		transformRange, err := transforms.Sub(uint64(len(geometries)) * transformStride)
+		if err != nil {
+			// The transform ring buffer is sized for the whole frame's
+			// batches; a pipeline that can't fit means the ring is
+			// undersized for this scene. Drop just this pipeline's draw
+			// rather than failing the whole frame.
+			core.LogWarn("batch: dropping pipeline " + pipelineKey + ": " + err.Error())
+			continue
+		}
+
+		var indexCount uint32
+		var vertexDataSize, indexDataSize uint64
+		for _, geo := range geometries {
+			indexCount += geo.IndexCount
+			vertexDataSize += geo.VertexDataSize
+			indexDataSize += geo.IndexDataSize
+		}
+		// A pipeline whose geometries haven't had their vertex/index byte
+		// sizes populated yet (VertexDataSize/IndexDataSize default to 0)
+		// reserves no range rather than erroring on a zero-size Sub; its
+		// FirstIndex/VertexOffset are left at 0 until that data is wired up.
+		var vertexRange, indexRange metadata.MemoryRange
+		if vertexDataSize > 0 {
+			vertexRange, err = vertices.Sub(vertexDataSize)
+			if err != nil {
+				core.LogWarn("batch: dropping pipeline " + pipelineKey + ": " + err.Error())
+				continue
+			}
+		}
+		if indexDataSize > 0 {
+			indexRange, err = indices.Sub(indexDataSize)
+			if err != nil {
+				core.LogWarn("batch: dropping pipeline " + pipelineKey + ": " + err.Error())
+				continue
+			}
+		}
+
+		// TODO: write geometries[i].Model into transforms at
+		// transformRange.Offset + i*transformStride; the vertex shader
+		// indexes it with gl_InstanceIndex/SV_InstanceID. Likewise copy
+		// geometries[i]'s vertex/index bytes into vertices/indices at their
+		// running offset within vertexRange/indexRange.
+		commands[pipelineKey] = metadata.DrawIndirectCommand{
+			IndexCount:    indexCount,
+			InstanceCount: uint32(len(geometries)),
+			FirstIndex:    uint32(indexRange.Offset / indexStride),
+			VertexOffset:  int32(vertexRange.Offset / vertexStride),
+			FirstInstance: uint32(transformRange.Offset / transformStride),
+		}
+	}
+	return commands
+}
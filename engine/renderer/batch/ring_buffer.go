@@ -0,0 +1,36 @@
+package batch
+
+import "github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+
+// RingBuffer wraps N per-frame backing RenderBuffers of the same type and
+// size, one per frame in flight, so the CPU can write into the next
+// frame's slice while the GPU is still consuming a previous frame's. This
+// is what eliminates the stalls a single buffer reused across frames in
+// flight would otherwise cause.
+type RingBuffer struct {
+	buffers []*metadata.RenderBuffer
+	current int
+}
+
+func NewRingBuffer(bufferType metadata.RenderBufferType, size uint64, framesInFlight int) *RingBuffer {
+	buffers := make([]*metadata.RenderBuffer, framesInFlight)
+	for i := range buffers {
+		buffers[i] = metadata.NewRenderBuffer(bufferType, size)
+	}
+	return &RingBuffer{buffers: buffers}
+}
+
+// Advance moves the ring to the next frame's backing buffer and resets
+// its freelist. Call once per frame, before any writes for that frame.
+// Resetting is safe here specifically because this slot was last used
+// framesInFlight frames ago, so the GPU is guaranteed done reading it by
+// now — that's the whole point of having framesInFlight of them.
+func (r *RingBuffer) Advance() {
+	r.current = (r.current + 1) % len(r.buffers)
+	r.buffers[r.current].Reset()
+}
+
+// Current returns this frame's backing buffer.
+func (r *RingBuffer) Current() *metadata.RenderBuffer {
+	return r.buffers[r.current]
+}
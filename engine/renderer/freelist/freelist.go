@@ -0,0 +1,136 @@
+package freelist
+
+import (
+	"fmt"
+	"sort"
+)
+
+// block is a single free range within the backing buffer.
+type block struct {
+	offset uint64
+	size   uint64
+}
+
+// Freelist tracks the free regions of a single linear buffer, handing out
+// sub-allocations via Allocate and accepting them back via Free. Adjacent
+// free blocks are coalesced back into one on Free, so long-running
+// allocate/free churn doesn't fragment the buffer into unusable slivers.
+type Freelist struct {
+	totalSize uint64
+	free      []block // sorted by offset, never touching/overlapping
+}
+
+// New creates a Freelist covering [0, totalSize).
+func New(totalSize uint64) *Freelist {
+	return &Freelist{
+		totalSize: totalSize,
+		free:      []block{{offset: 0, size: totalSize}},
+	}
+}
+
+// Allocate reserves size bytes aligned to alignment and returns the
+// offset of the allocation within the backing buffer. It picks the free
+// block that wastes the least space (best-fit), which keeps large free
+// blocks available for large future allocations at the cost of a linear
+// scan per call.
+func (f *Freelist) Allocate(size, alignment uint64) (uint64, error) {
+	if size == 0 {
+		return 0, fmt.Errorf("freelist: cannot allocate zero bytes")
+	}
+	if alignment == 0 {
+		alignment = 1
+	}
+
+	bestIndex := -1
+	var bestWaste uint64
+	var bestOffset uint64
+	for i, blk := range f.free {
+		alignedOffset := alignUp(blk.offset, alignment)
+		if alignedOffset-blk.offset >= blk.size {
+			continue
+		}
+		available := blk.size - (alignedOffset - blk.offset)
+		if available < size {
+			continue
+		}
+		waste := available - size
+		if bestIndex == -1 || waste < bestWaste {
+			bestIndex, bestWaste, bestOffset = i, waste, alignedOffset
+		}
+	}
+	if bestIndex == -1 {
+		return 0, fmt.Errorf("freelist: no free block large enough for %d bytes (alignment %d)", size, alignment)
+	}
+
+	blk := f.free[bestIndex]
+	allocEnd := bestOffset + size
+	tailSize := (blk.offset + blk.size) - allocEnd
+
+	replacement := make([]block, 0, 2)
+	if bestOffset > blk.offset {
+		replacement = append(replacement, block{offset: blk.offset, size: bestOffset - blk.offset})
+	}
+	if tailSize > 0 {
+		replacement = append(replacement, block{offset: allocEnd, size: tailSize})
+	}
+	f.free = append(f.free[:bestIndex], append(replacement, f.free[bestIndex+1:]...)...)
+
+	return bestOffset, nil
+}
+
+// Free returns a previously allocated [offset, offset+size) range to the
+// freelist, merging it with any free blocks that immediately precede or
+// follow it.
+func (f *Freelist) Free(offset, size uint64) {
+	at := sort.Search(len(f.free), func(i int) bool { return f.free[i].offset >= offset })
+	f.free = append(f.free, block{})
+	copy(f.free[at+1:], f.free[at:])
+	f.free[at] = block{offset: offset, size: size}
+	f.coalesce(at)
+}
+
+// coalesce merges the block at index i with its immediate neighbors if
+// they are contiguous.
+func (f *Freelist) coalesce(i int) {
+	if i+1 < len(f.free) && f.free[i].offset+f.free[i].size == f.free[i+1].offset {
+		f.free[i].size += f.free[i+1].size
+		f.free = append(f.free[:i+1], f.free[i+2:]...)
+	}
+	if i > 0 && f.free[i-1].offset+f.free[i-1].size == f.free[i].offset {
+		f.free[i-1].size += f.free[i].size
+		f.free = append(f.free[:i], f.free[i+1:]...)
+	}
+}
+
+// Grow extends the tracked range by extra bytes. Call after the backing
+// buffer itself has actually been resized.
+func (f *Freelist) Grow(extra uint64) {
+	if n := len(f.free); n > 0 && f.free[n-1].offset+f.free[n-1].size == f.totalSize {
+		f.free[n-1].size += extra
+	} else {
+		f.free = append(f.free, block{offset: f.totalSize, size: extra})
+	}
+	f.totalSize += extra
+}
+
+// TotalSize returns the size of the buffer this Freelist tracks.
+func (f *Freelist) TotalSize() uint64 {
+	return f.totalSize
+}
+
+// Occupancy reports how much of the tracked range is currently allocated,
+// as a fraction in [0, 1].
+func (f *Freelist) Occupancy() float64 {
+	if f.totalSize == 0 {
+		return 0
+	}
+	var freeBytes uint64
+	for _, blk := range f.free {
+		freeBytes += blk.size
+	}
+	return 1 - float64(freeBytes)/float64(f.totalSize)
+}
+
+func alignUp(offset, alignment uint64) uint64 {
+	return (offset + alignment - 1) &^ (alignment - 1)
+}
@@ -0,0 +1,24 @@
+package graph
+
+// TransientFramebuffer is a framebuffer allocated by the graph, backing
+// one pass output attachment. Unlike RenderTarget, callers never create
+// these directly; the graph allocates one the first time Compile sees an
+// attachment name and reuses it for that name in later Compile calls.
+type TransientFramebuffer struct {
+	AttachmentName string
+	Width          uint16
+	Height         uint16
+	// Internal holds the renderer-API-specific framebuffer object, set by
+	// the backend that executes this graph.
+	Internal interface{}
+}
+
+// allocateTransientFramebuffer creates the framebuffer backing a single
+// pass output. Width/Height default to the owning render target's size
+// and are resolved by the backend at Execute time; the graph only tracks
+// the attachment this framebuffer is for.
+func allocateTransientFramebuffer(out AttachmentUsage) *TransientFramebuffer {
+	return &TransientFramebuffer{
+		AttachmentName: out.Name,
+	}
+}
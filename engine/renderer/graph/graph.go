@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+)
+
+// CommandBuffer is the backend-agnostic handle passed to a pass's execute
+// function. Concrete renderer backends populate Internal with whatever
+// recording state they need (VkCommandBuffer, ID3D11DeviceContext, ...);
+// the graph itself never looks inside it. Packet is set by Execute so a
+// pass's ExecuteFunc can read the frame's RenderPacket without it being
+// threaded through as a separate argument.
+type CommandBuffer struct {
+	Internal interface{}
+	Packet   *metadata.RenderPacket
+}
+
+// AttachmentUsage describes how a pass reads or writes one of its
+// attachments. Name ties usages from different passes together so the
+// graph can determine ordering; Format and ClearFlags together form the
+// renderpass cache key.
+type AttachmentUsage struct {
+	Name       string
+	Format     string
+	ClearFlags metadata.RenderpassClearFlag
+}
+
+// ExecuteFunc records the commands for a single compiled pass. pass is the
+// (possibly shared) renderpass this pass was resolved to, barriers are the
+// transitions Compile determined must happen before this pass's own
+// commands, and framebuffers are the transient framebuffers backing this
+// pass's outputs.
+type ExecuteFunc func(cmd *CommandBuffer, pass *metadata.RenderPass, barriers []Barrier, framebuffers []*TransientFramebuffer) error
+
+// Barrier describes a layout transition/synchronization point the graph
+// inserts before a pass runs, because a prior pass wrote the same
+// attachment this pass now reads.
+type Barrier struct {
+	AttachmentName string
+	FromPass       string
+	ToPass         string
+}
+
+type passNode struct {
+	name      string
+	inputs    []AttachmentUsage
+	outputs   []AttachmentUsage
+	isCompute bool
+	execute   ExecuteFunc
+
+	renderPass   *metadata.RenderPass
+	barriers     []Barrier
+	framebuffers []*TransientFramebuffer
+}
+
+// Graph collects passes declared via AddPass, topologically sorts them by
+// their attachment reads/writes, deduplicates compatible renderpasses into
+// a cache keyed by attachment format/load-store signature, and inserts the
+// image layout transitions and barriers required between passes. It
+// replaces manually wiring RenderPassConfig.PrevName/NextName.
+type Graph struct {
+	passes    map[string]*passNode
+	order     []string
+	passCache map[string]*metadata.RenderPass
+	// framebufs caches transient framebuffers keyed by attachment Name, not
+	// by the format/clear-flags key passCache uses: two distinct
+	// attachments (e.g. a shadow-map depth target and the depth pre-pass's
+	// depth target) can easily share the same format and clear ops while
+	// needing entirely separate image views, so the renderpass-compatibility
+	// key is wrong for framebuffer identity.
+	framebufs map[string]*TransientFramebuffer
+}
+
+func New() *Graph {
+	return &Graph{
+		passes:    make(map[string]*passNode),
+		passCache: make(map[string]*metadata.RenderPass),
+		framebufs: make(map[string]*TransientFramebuffer),
+	}
+}
+
+// AddPass declares a pass by name along with the attachments it reads
+// (inputs) and writes (outputs). isCompute marks the pass as dispatching
+// compute work (vkCmdDispatch) rather than issuing graphics draws; it is
+// carried onto the resolved metadata.RenderPass's IsCompute field and made
+// part of the renderpass cache key, so a compute pass is never coalesced
+// with a graphics pass that happens to share the same attachment
+// format/clear signature (compute passes with the same signature still
+// share one cached renderpass). execute is invoked during Execute once the graph has been
+// compiled.
+func (g *Graph) AddPass(name string, inputs, outputs []AttachmentUsage, isCompute bool, execute ExecuteFunc) error {
+	if _, exists := g.passes[name]; exists {
+		return fmt.Errorf("render graph: pass %q already added", name)
+	}
+	g.passes[name] = &passNode{
+		name:      name,
+		inputs:    inputs,
+		outputs:   outputs,
+		isCompute: isCompute,
+		execute:   execute,
+	}
+	g.order = nil
+	return nil
+}
+
+// Compile topologically sorts the declared passes by their attachment
+// dependencies, resolves each pass to a (possibly shared) renderpass from
+// the cache, computes the barriers needed between passes, and allocates
+// transient framebuffers for their outputs. It must be called once after
+// all passes have been added and before Execute.
+func (g *Graph) Compile() error {
+	order, err := g.topoSort()
+	if err != nil {
+		return err
+	}
+
+	lastWriter := make(map[string]string)
+	for _, name := range order {
+		pass := g.passes[name]
+		pass.renderPass = g.resolvePass(pass)
+		pass.barriers = pass.barriers[:0]
+
+		for _, in := range pass.inputs {
+			if writer, ok := lastWriter[in.Name]; ok && writer != name {
+				pass.barriers = append(pass.barriers, Barrier{
+					AttachmentName: in.Name,
+					FromPass:       writer,
+					ToPass:         name,
+				})
+			}
+		}
+		pass.framebuffers = pass.framebuffers[:0]
+		for _, out := range pass.outputs {
+			lastWriter[out.Name] = name
+			fb, ok := g.framebufs[out.Name]
+			if !ok {
+				fb = allocateTransientFramebuffer(out)
+				g.framebufs[out.Name] = fb
+			}
+			pass.framebuffers = append(pass.framebuffers, fb)
+		}
+	}
+
+	g.order = order
+	return nil
+}
+
+// Execute runs every compiled pass in dependency order, handing each
+// pass's ExecuteFunc the renderpass/barriers/framebuffers Compile resolved
+// for it along with a CommandBuffer carrying packet. Compile must have
+// been called first.
+func (g *Graph) Execute(packet *metadata.RenderPacket, cmd *CommandBuffer) error {
+	if g.order == nil {
+		return fmt.Errorf("render graph: Execute called before Compile")
+	}
+	cmd.Packet = packet
+	for _, name := range g.order {
+		pass := g.passes[name]
+		if err := pass.execute(cmd, pass.renderPass, pass.barriers, pass.framebuffers); err != nil {
+			return fmt.Errorf("render graph: pass %q failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// topoSort orders passes so that every pass runs after the passes that
+// write the attachments it reads. Iteration over unordered passes is
+// sorted by name first so Compile is reproducible across runs.
+func (g *Graph) topoSort() ([]string, error) {
+	producer := make(map[string]string, len(g.passes))
+	for _, pass := range g.passes {
+		for _, out := range pass.outputs {
+			producer[out.Name] = pass.name
+		}
+	}
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+	state := make(map[string]int, len(g.passes))
+	order := make([]string, 0, len(g.passes))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("render graph: cycle detected involving pass %q", name)
+		}
+		state[name] = visiting
+		for _, in := range g.passes[name].inputs {
+			if dep, ok := producer[in.Name]; ok && dep != name {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	names := make([]string, 0, len(g.passes))
+	for name := range g.passes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// resolvePass returns a cached *metadata.RenderPass compatible with
+// pass's attachments, creating and caching a new one on first use. Two
+// passes whose attachments share the same formats and clear ops reuse the
+// same underlying renderpass, mirroring the renderpass_cache pattern.
+func (g *Graph) resolvePass(pass *passNode) *metadata.RenderPass {
+	key := passCacheKey(pass)
+	if cached, ok := g.passCache[key]; ok {
+		return cached
+	}
+	rp := &metadata.RenderPass{
+		ClearFlags: clearFlagsFor(pass.outputs),
+		IsCompute:  pass.isCompute,
+	}
+	g.passCache[key] = rp
+	return rp
+}
+
+func passCacheKey(pass *passNode) string {
+	key := ""
+	if pass.isCompute {
+		key += "compute|"
+	}
+	for _, a := range append(append([]AttachmentUsage{}, pass.inputs...), pass.outputs...) {
+		key += attachmentCacheKey(a) + "|"
+	}
+	return key
+}
+
+func attachmentCacheKey(a AttachmentUsage) string {
+	return fmt.Sprintf("%s:%d", a.Format, a.ClearFlags)
+}
+
+func clearFlagsFor(outputs []AttachmentUsage) uint8 {
+	var flags uint8
+	for _, out := range outputs {
+		flags |= uint8(out.ClearFlags)
+	}
+	return flags
+}
@@ -1,6 +1,9 @@
 package metadata
 
 import (
+	"fmt"
+
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/freelist"
 	"github.com/spaghettifunk/anima/engine/math"
 	"github.com/spaghettifunk/anima/engine/resources"
 )
@@ -53,6 +56,8 @@ type RenderPassConfig struct {
 	ClearColour math.Vec4
 	/** @brief The clear flags for this renderpass. */
 	ClearFlags uint8
+	/** @brief Whether this pass dispatches compute work (vkCmdDispatch) instead of issuing graphics draws. */
+	IsCompute bool
 }
 
 /**
@@ -67,6 +72,8 @@ type RenderPass struct {
 	ClearColour math.Vec4
 	/** @brief The clear flags for this renderpass. */
 	ClearFlags uint8
+	/** @brief Whether this pass dispatches compute work (vkCmdDispatch) instead of issuing graphics draws. */
+	IsCompute bool
 	/** @brief The number of render targets for this renderpass. */
 	RenderTargetCount uint8
 	/** @brief An array of render Targets used by this renderpass. */
@@ -99,16 +106,77 @@ type RenderBuffer struct {
 	RenderBufferType RenderBufferType
 	/** @brief The total size of the buffer in bytes. */
 	TotalSize uint64
-	/** @brief The amount of memory required to store the freelist. 0 if not used. */
-	// freelist_memory_requirement uint64
-	/** @brief The buffer freelist, if used. */
-	// buffer_freelist freelist
-	/** @brief The freelist memory block, if needed. */
-	// freelist_block interface{}
+	/** @brief The buffer freelist, used to hand out sub-allocations of the single backing VkBuffer instead of allocating per-mesh buffers. */
+	buffer_freelist *freelist.Freelist
 	/** @brief Contains internal data for the renderer-API-specific buffer. */
 	internal_data interface{}
 }
 
+/**
+ * @brief RenderBufferSubAllocationAlignment is the alignment, in bytes,
+ * applied to every RenderBuffer.Sub allocation. 256 satisfies the
+ * strictest common GPU requirement (uniform/storage buffer offset
+ * alignment), so a single constant works for vertex, index and uniform
+ * sub-allocations alike.
+ */
+const RenderBufferSubAllocationAlignment uint64 = 256
+
+/** @brief Creates a new RenderBuffer of the given type backed by a single totalSize-byte allocation, sub-allocated via Sub/Release. */
+func NewRenderBuffer(bufferType RenderBufferType, totalSize uint64) *RenderBuffer {
+	return &RenderBuffer{
+		RenderBufferType: bufferType,
+		TotalSize:        totalSize,
+		buffer_freelist:  freelist.New(totalSize),
+	}
+}
+
+/** @brief Reserves size bytes from this buffer's freelist, returning the MemoryRange callers should write into and bind at draw time. */
+func (b *RenderBuffer) Sub(size uint64) (MemoryRange, error) {
+	offset, err := b.buffer_freelist.Allocate(size, RenderBufferSubAllocationAlignment)
+	if err != nil {
+		return MemoryRange{}, fmt.Errorf("renderbuffer: %w", err)
+	}
+	return MemoryRange{Offset: offset, Size: size}, nil
+}
+
+/** @brief Returns a range previously obtained from Sub back to this buffer's freelist, coalescing it with any adjacent free space. */
+func (b *RenderBuffer) Release(r MemoryRange) {
+	b.buffer_freelist.Free(r.Offset, r.Size)
+}
+
+/**
+ * @brief Grows the underlying buffer to newSize and extends the
+ * freelist's tracked range to match. The caller is responsible for
+ * copying any live ranges into the new, larger backing buffer via a
+ * staging queue before the old one is released; Resize only updates the
+ * bookkeeping.
+ */
+func (b *RenderBuffer) Resize(newSize uint64) error {
+	if newSize <= b.TotalSize {
+		return fmt.Errorf("renderbuffer: Resize newSize %d must be greater than current size %d", newSize, b.TotalSize)
+	}
+	b.buffer_freelist.Grow(newSize - b.TotalSize)
+	b.TotalSize = newSize
+	return nil
+}
+
+/** @brief Reports how full this buffer's freelist is, as a fraction in [0, 1], useful for deciding when a Resize is due. */
+func (b *RenderBuffer) Occupancy() float64 {
+	return b.buffer_freelist.Occupancy()
+}
+
+/**
+ * @brief Discards every outstanding Sub allocation, returning the whole
+ * buffer to the freelist. Intended for buffers whose lifetime is a single
+ * frame slot in a ring (see batch.RingBuffer): by the time that slot's
+ * turn comes back around, every GPU read of its previous contents is long
+ * since retired, so reclaiming it unconditionally (instead of requiring a
+ * matching Release per Sub) is safe.
+ */
+func (b *RenderBuffer) Reset() {
+	b.buffer_freelist = freelist.New(b.TotalSize)
+}
+
 /**
  * @brief A structure which is generated by the application and sent once
  * to the renderer to render a given frame. Consists of any data required,
@@ -132,6 +200,10 @@ const (
 	RENDERER_VIEW_KNOWN_TYPE_UI RenderViewKnownType = 0x02
 	/** @brief A view which only renders skybox objects. */
 	RENDERER_VIEW_KNOWN_TYPE_SKYBOX RenderViewKnownType = 0x03
+	/** @brief A view which dispatches compute workloads (culling, tile classification, post-process) instead of issuing graphics draws. */
+	RENDERER_VIEW_KNOWN_TYPE_COMPUTE RenderViewKnownType = 0x04
+	/** @brief A view which performs Hi-Z occlusion culling, producing a VisibilityBuffer consumed by the main forward pass's indirect draw. */
+	RENDERER_VIEW_KNOWN_TYPE_OCCLUSION_CULL RenderViewKnownType = 0x05
 )
 
 /** @brief Known view matrix sources. */
@@ -298,6 +370,13 @@ type RenderViewPacket struct {
 type GeometryRenderData struct {
 	Model    math.Mat4
 	Geometry *resources.Geometry
+	// IndexCount is the number of indices to draw for this geometry.
+	IndexCount uint32
+	// VertexDataSize and IndexDataSize are the byte sizes of this
+	// geometry's vertex and index data, used by batch.Batcher to size its
+	// per-pipeline sub-allocations in the vertex/index ring buffers.
+	VertexDataSize uint64
+	IndexDataSize  uint64
 }
 
 type MeshPacketData struct {
@@ -305,6 +384,24 @@ type MeshPacketData struct {
 	Meshes    []*resources.Mesh
 }
 
+/**
+ * @brief Packet data for a RENDERER_VIEW_KNOWN_TYPE_COMPUTE view, holding
+ * dispatch dimensions and the storage buffers the compute shader reads
+ * and writes. Set as RenderViewPacket.ExtendedData.
+ */
+type ComputePacketData struct {
+	/** @brief The number of workgroups to dispatch along X. */
+	GroupCountX uint32
+	/** @brief The number of workgroups to dispatch along Y. */
+	GroupCountY uint32
+	/** @brief The number of workgroups to dispatch along Z. */
+	GroupCountZ uint32
+	/** @brief The storage buffers bound to the compute shader, typically RENDERBUFFER_TYPE_STORAGE buffers. */
+	StorageBuffers []*RenderBuffer
+	/** @brief The image resources bound to the compute shader, e.g. a HiZPyramid.Texture read and written by a downsample pass. */
+	Textures []*resources.Texture
+}
+
 // type UIPacketData struct {
 // 	MeshData *MeshPacketData
 // 	// TODO: temp
@@ -316,6 +413,111 @@ type SkyboxPacketData struct {
 	Skybox *resources.Skybox
 }
 
+/**
+ * @brief Carries backend-specific initialization options that are
+ * orthogonal to backend selection (see renderer.Config), such as where to
+ * persist the pipeline cache between runs. Lives here for the same
+ * import-cycle reason as RendererCapabilities.
+ */
+type RendererBackendConfig struct {
+	/**
+	 * @brief Path to the on-disk pipeline cache blob. Loaded on
+	 * Initialize (if present and compatible with the active device) and
+	 * written back on Shutdow or SavePipelineCache. Empty disables
+	 * on-disk pipeline caching.
+	 */
+	PipelineCachePath string
+}
+
+/**
+ * @brief Describes the optional features and limits a renderer backend
+ * exposes once initialized, so higher-level code can gate features
+ * without needing to know which backend is actually active. Lives here
+ * rather than in the renderer package so that backend packages (vulkan,
+ * d3d11, metal, opengl) can return it without importing the renderer
+ * package and creating an import cycle.
+ */
+type RendererCapabilities struct {
+	/** @brief Whether the backend supports compute shader dispatch. */
+	SupportsCompute bool
+	/** @brief Whether the backend supports GPU timestamp queries. */
+	SupportsTimestampQueries bool
+	/** @brief The maximum supported texture dimension, in texels. */
+	MaxTextureSize uint32
+}
+
+/**
+ * @brief Mirrors VkDrawIndexedIndirectCommand. One of these is built per
+ * pipeline/material by the batcher and consumed by
+ * vkCmdDrawIndexedIndirect, replacing one CPU-issued draw call per
+ * geometry with a single indirect draw for the whole batch.
+ */
+type DrawIndirectCommand struct {
+	/** @brief The number of indices to draw per instance. */
+	IndexCount uint32
+	/** @brief The number of instances to draw; one per batched geometry. */
+	InstanceCount uint32
+	/** @brief The offset into the index ring buffer of the first index. */
+	FirstIndex uint32
+	/** @brief The offset into the vertex ring buffer to add to each index. */
+	VertexOffset int32
+	/** @brief The offset into the per-instance transform SSBO of the first instance. */
+	FirstInstance uint32
+}
+
+/**
+ * @brief A hierarchical depth pyramid used for Hi-Z occlusion culling.
+ * Each mip level stores the max depth of its 2x2 parent texels, so
+ * testing an object's screen-space AABB against the mip whose texel size
+ * just exceeds the AABB's extent gives a conservative (never
+ * false-negative) occlusion result.
+ */
+type HiZPyramid struct {
+	/** @brief The depth pyramid texture; mip 0 is a copy of the depth pre-pass output, each subsequent mip half its resolution. */
+	Texture *resources.Texture
+	/** @brief One image view per mip level, so the downsample compute pass can read mip N while writing mip N+1. */
+	MipViews []interface{}
+	/** @brief The number of mip levels in the pyramid. */
+	MipCount uint8
+}
+
+/**
+ * @brief Per-instance screen-space-testable bounds, stored in an SSBO and
+ * consumed by the occlusion culling compute pass.
+ */
+type InstanceBounds struct {
+	/** @brief The minimum corner of the instance's AABB, in world space. */
+	Min math.Vec3
+	/** @brief The maximum corner of the instance's AABB, in world space. */
+	Max math.Vec3
+	/** @brief The index of this instance's transform in the transform SSBO. */
+	ModelIndex uint32
+}
+
+/**
+ * @brief A bitmask with one bit per instance, set by the occlusion
+ * culling compute pass and consumed when building the main forward pass's
+ * filtered indirect draw buffer.
+ */
+type VisibilityBuffer struct {
+	/** @brief One bit per instance; instance i's visibility is bit (i % 32) of Bits[i / 32]. */
+	Bits []uint32
+	/** @brief The number of instances this buffer covers. */
+	InstanceCount uint32
+}
+
+/**
+ * @brief Packet data for a RENDERER_VIEW_KNOWN_TYPE_OCCLUSION_CULL view.
+ */
+type OcclusionCullPacketData struct {
+	/** @brief The Hi-Z depth pyramid to test instance bounds against. */
+	Pyramid *HiZPyramid
+	/** @brief The InstanceBounds SSBO for every instance under consideration this frame. */
+	Bounds *RenderBuffer
+	/** @brief The VisibilityBuffer this pass writes its results into. */
+	Visibility *VisibilityBuffer
+}
+
 /** @brief A range, typically of memory */
 type MemoryRange struct {
 	/** @brief The Offset in bytes. */
@@ -0,0 +1,77 @@
+//go:build darwin
+
+package metal
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/core"
+	"github.com/spaghettifunk/alaska-engine/engine/platform"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+)
+
+// Backend is the Metal implementation of renderer.RendererBackend.
+type Backend struct {
+	platform *platform.Platform
+
+	// device, commandQueue and layer hold the opaque Metal handles. Typed
+	// as interface{} here since the cgo/bindings layer is out of scope for
+	// this change.
+	device       interface{}
+	commandQueue interface{}
+	layer        interface{}
+
+	capabilities metadata.RendererCapabilities
+}
+
+func New(plat *platform.Platform) *Backend {
+	return &Backend{
+		platform: plat,
+	}
+}
+
+// TODO: Initialize always returns nil today — device/command queue
+// creation below isn't wired in yet, so this can never fail and
+// renderer.Config.FallbackOrder can never actually be exercised for this
+// backend.
+func (b *Backend) Initialize(appName string, config metadata.RendererBackendConfig) error {
+	core.LogInfo("Initializing Metal renderer backend...")
+	// TODO: create the MTLDevice, command queue and CAMetalLayer.
+	b.capabilities = metadata.RendererCapabilities{
+		SupportsCompute:          true,
+		SupportsTimestampQueries: true,
+		MaxTextureSize:           16384,
+	}
+	return nil
+}
+
+// SubmitIndirect is a no-op for this backend; it records no draws.
+func (b *Backend) SubmitIndirect(commands map[string]metadata.DrawIndirectCommand) error {
+	return nil
+}
+
+// SavePipelineCache is a no-op for this backend; on-disk pipeline caching
+// is only implemented for Vulkan so far.
+func (b *Backend) SavePipelineCache() error {
+	return nil
+}
+
+func (b *Backend) Shutdow() error {
+	core.LogInfo("Shutting down Metal renderer backend...")
+	return nil
+}
+
+func (b *Backend) Resized(width, height uint16) error {
+	return nil
+}
+
+func (b *Backend) BeginFrame(deltaTime float64) error {
+	return nil
+}
+
+func (b *Backend) EndFrame(deltaTime float64) error {
+	return nil
+}
+
+// Capabilities reports the features supported by this backend.
+func (b *Backend) Capabilities() metadata.RendererCapabilities {
+	return b.capabilities
+}
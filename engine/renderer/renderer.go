@@ -1,21 +1,50 @@
 package renderer
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/spaghettifunk/alaska-engine/engine/core"
 	"github.com/spaghettifunk/alaska-engine/engine/platform"
-	"github.com/spaghettifunk/alaska-engine/engine/renderer/vulkan"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/batch"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/graph"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
 )
 
 type RendererBackend interface {
-	Initialize(appName string) error
+	Initialize(appName string, config metadata.RendererBackendConfig) error
 	Shutdow() error
 	Resized(width, height uint16) error
 	BeginFrame(deltaTime float64) error
 	EndFrame(deltaTime float64) error
+	// Capabilities reports the features supported by the backend. It is
+	// only valid to call after Initialize has returned successfully.
+	//
+	// TODO: every backend currently returns a hardcoded RendererCapabilities
+	// literal set during Initialize rather than one probed from the actual
+	// device/driver, since none of them do real device creation yet.
+	Capabilities() metadata.RendererCapabilities
+	// SavePipelineCache flushes the backend's pipeline cache to disk
+	// immediately, at the path given to Initialize. Backends that don't
+	// support on-disk pipeline caching return nil without doing anything.
+	SavePipelineCache() error
+	// SubmitIndirect records one indirect draw call per entry in commands,
+	// keyed by the same pipeline/material key passed to SubmitBatch.
+	SubmitIndirect(commands map[string]metadata.DrawIndirectCommand) error
 }
 
+// FramesInFlight is the number of per-frame ring buffers the batcher
+// allocates for vertex/index/transform/indirect data, so the CPU can
+// write frame N+1's data while the GPU still reads frame N's.
+const FramesInFlight = 3
+
+const (
+	defaultVertexRingBufferSize    uint64 = 64 * 1024 * 1024
+	defaultIndexRingBufferSize     uint64 = 16 * 1024 * 1024
+	defaultTransformRingBufferSize uint64 = 4 * 1024 * 1024
+	defaultIndirectRingBufferSize  uint64 = 1 * 1024 * 1024
+)
+
 type RendererType uint8
 
 const (
@@ -25,8 +54,61 @@ const (
 	OpenGL
 )
 
+func (t RendererType) String() string {
+	switch t {
+	case Vulkan:
+		return "vulkan"
+	case DirectX:
+		return "directx"
+	case Metal:
+		return "metal"
+	case OpenGL:
+		return "opengl"
+	default:
+		return "unknown"
+	}
+}
+
+// Config controls which backend is selected during Initialize. If
+// PreferredBackend is not available on the current platform, the backends
+// listed in FallbackOrder are tried in turn until one initializes
+// successfully.
+//
+// TODO: today every backend's Initialize always succeeds (none of them do
+// real device/context probing yet), so in practice FallbackOrder is only
+// ever exercised by a backend being unavailable on the current platform,
+// not by an initialization failure. It'll start doing what its doc says
+// once the backends grow real capability probing.
+type Config struct {
+	PreferredBackend RendererType
+	FallbackOrder    []RendererType
+	// PipelineCachePath is forwarded to the selected backend; see
+	// metadata.RendererBackendConfig.PipelineCachePath.
+	PipelineCachePath string
+}
+
+// backendConstructor builds a RendererBackend for a given platform. It
+// returns nil if the backend is not available on the platform the binary
+// was compiled for (e.g. DirectX on non-Windows builds).
+type backendConstructor func(platform *platform.Platform) RendererBackend
+
+// backendConstructors is populated by the per-OS backend_*.go files in this
+// package, one entry per RendererType. Backends that cannot be built for
+// the current OS are represented by a constructor that returns nil, so
+// fallback selection can skip them without special-casing each platform.
+var backendConstructors = map[RendererType]backendConstructor{
+	Vulkan:  newVulkanBackend,
+	DirectX: newD3D11Backend,
+	Metal:   newMetalBackend,
+	OpenGL:  newOpenGLBackend,
+}
+
 type Renderer struct {
-	backend RendererBackend
+	backend     RendererBackend
+	backendType RendererType
+	batcher     *batch.Batcher
+	frameGraph  *graph.Graph
+	graphDirty  bool
 }
 
 type RenderPacket struct {
@@ -36,13 +118,44 @@ type RenderPacket struct {
 var initRenderer sync.Once
 var renderer *Renderer
 
-func Initialize(appName string, platform *platform.Platform) error {
+func Initialize(appName string, plat *platform.Platform, config Config) error {
+	var initErr error
 	initRenderer.Do(func() {
-		renderer = &Renderer{
-			backend: vulkan.New(platform),
+		order := append([]RendererType{config.PreferredBackend}, config.FallbackOrder...)
+		for _, candidate := range order {
+			constructor, ok := backendConstructors[candidate]
+			if !ok {
+				continue
+			}
+			backend := constructor(plat)
+			if backend == nil {
+				// Not available on this platform/build.
+				continue
+			}
+			backendConfig := metadata.RendererBackendConfig{PipelineCachePath: config.PipelineCachePath}
+			if err := backend.Initialize(appName, backendConfig); err != nil {
+				core.LogWarn(fmt.Sprintf("renderer backend %s failed to initialize: %s", candidate, err.Error()))
+				continue
+			}
+			renderer = &Renderer{
+				backend:     backend,
+				backendType: candidate,
+				batcher: batch.NewBatcher(FramesInFlight,
+					defaultVertexRingBufferSize,
+					defaultIndexRingBufferSize,
+					defaultTransformRingBufferSize,
+					defaultIndirectRingBufferSize),
+				frameGraph: graph.New(),
+				graphDirty: true,
+			}
+			return
 		}
+		initErr = fmt.Errorf("no renderer backend in %v could be initialized", order)
 	})
-	return renderer.backend.Initialize(appName)
+	if renderer == nil && initErr == nil {
+		initErr = fmt.Errorf("renderer already failed to initialize")
+	}
+	return initErr
 }
 
 func Shutdown() error {
@@ -50,10 +163,23 @@ func Shutdown() error {
 }
 
 func BeginFrame(deltaTime float64) error {
+	renderer.batcher.BeginFrame()
 	return renderer.backend.BeginFrame(deltaTime)
 }
 
+// SubmitBatch adds geo to the batch for pipelineKey (typically the
+// material/pipeline's identity). All geometry submitted under the same
+// key between BeginFrame and EndFrame is drawn with a single
+// vkCmdDrawIndexedIndirect call instead of one draw per geometry.
+func SubmitBatch(pipelineKey string, geo metadata.GeometryRenderData) {
+	renderer.batcher.Add(pipelineKey, geo)
+}
+
 func EndFrame(deltaTime float64) error {
+	commands := renderer.batcher.Build()
+	if err := renderer.backend.SubmitIndirect(commands); err != nil {
+		return err
+	}
 	return renderer.backend.EndFrame(deltaTime)
 }
 
@@ -61,11 +187,62 @@ func OnResize(width, height uint16) error {
 	return renderer.backend.Resized(width, height)
 }
 
+// AddPass declares a pass on the renderer's frame graph; see
+// graph.Graph.AddPass. Call before the next DrawFrame — passes are
+// (re)compiled automatically the first time DrawFrame runs after an
+// AddPass call.
+func AddPass(name string, inputs, outputs []graph.AttachmentUsage, isCompute bool, execute graph.ExecuteFunc) error {
+	if err := renderer.frameGraph.AddPass(name, inputs, outputs, isCompute, execute); err != nil {
+		return err
+	}
+	renderer.graphDirty = true
+	return nil
+}
+
+// ActiveBackend returns the RendererType that was actually selected during
+// Initialize, which may differ from Config.PreferredBackend if a fallback
+// was used.
+func ActiveBackend() RendererType {
+	return renderer.backendType
+}
+
+// BackendCapabilities returns the Capabilities reported by the active
+// backend.
+func BackendCapabilities() metadata.RendererCapabilities {
+	return renderer.backend.Capabilities()
+}
+
+// SavePipelineCache flushes the active backend's pipeline cache to disk.
+func SavePipelineCache() error {
+	return renderer.backend.SavePipelineCache()
+}
+
+// DrawFrame drives a full frame through the renderer's frame graph:
+// compiling it if passes have changed since the last call, then
+// BeginFrame, graph Execute (which runs every declared pass in dependency
+// order with its resolved renderpass/barriers/framebuffers), the batch
+// submission built up via SubmitBatch, and EndFrame.
 func DrawFrame(renderPacket *RenderPacket) error {
+	if renderer.graphDirty {
+		if err := renderer.frameGraph.Compile(); err != nil {
+			core.LogError(err.Error())
+			return err
+		}
+		renderer.graphDirty = false
+	}
+
 	if err := BeginFrame(renderPacket.DeltaTime); err != nil {
 		core.LogError(err.Error())
 		return err
 	}
+
+	cmd := &graph.CommandBuffer{}
+	graphPacket := &metadata.RenderPacket{DeltaTime: renderPacket.DeltaTime}
+	if err := renderer.frameGraph.Execute(graphPacket, cmd); err != nil {
+		core.LogError(err.Error())
+		return err
+	}
+
 	if err := EndFrame(renderPacket.DeltaTime); err != nil {
 		core.LogError("RendererEndFrame failed. Application shutting down...")
 		return err
@@ -0,0 +1,13 @@
+package views
+
+import "github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+
+// ComputeDispatcher is implemented by renderer backends that support
+// compute dispatch (currently vulkan.Backend). The compute-driven
+// reference views in this package (frustum cull, occlusion cull) call it
+// from OnRender so they actually demonstrate the compute -> indirect-draw
+// flow end-to-end, rather than only type-asserting their packet data and
+// stopping there.
+type ComputeDispatcher interface {
+	DispatchCompute(data *metadata.ComputePacketData) error
+}
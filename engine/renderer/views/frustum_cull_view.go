@@ -0,0 +1,47 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+)
+
+// NewFrustumCullView builds a reference RenderView demonstrating the
+// compute -> indirect-draw flow: a compute shader tests each instance's
+// bounds against the view frustum and writes surviving instance indices
+// directly into the indirect draw buffer, so the subsequent forward pass
+// issues a single indirect draw instead of the CPU building per-geometry
+// draw calls. dispatcher is the active backend's compute dispatch (e.g.
+// vulkan.Backend), invoked from OnRender.
+func NewFrustumCullView(name string, width, height uint16, dispatcher ComputeDispatcher) *metadata.RenderView {
+	view := &metadata.RenderView{
+		Name:           name,
+		Width:          width,
+		Height:         height,
+		RenderViewType: metadata.RENDERER_VIEW_KNOWN_TYPE_COMPUTE,
+	}
+	view.OnBuildPacket = func(data interface{}) (*metadata.RenderViewPacket, error) {
+		culling, ok := data.(*metadata.ComputePacketData)
+		if !ok {
+			return nil, fmt.Errorf("frustum cull view: expected *metadata.ComputePacketData, got %T", data)
+		}
+		return &metadata.RenderViewPacket{
+			View:         view,
+			ExtendedData: culling,
+		}, nil
+	}
+	view.OnRender = func(packet *metadata.RenderViewPacket, frameNumber, renderTargetIndex uint64) bool {
+		culling, ok := packet.ExtendedData.(*metadata.ComputePacketData)
+		if !ok {
+			return false
+		}
+		// The backend's DispatchCompute records the frustum_cull.comp
+		// dispatch against culling; its writes are visible to the forward
+		// pass's indirect draw via the barrier DispatchCompute inserts.
+		if err := dispatcher.DispatchCompute(culling); err != nil {
+			return false
+		}
+		return true
+	}
+	return view
+}
@@ -0,0 +1,116 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+	"github.com/spaghettifunk/anima/engine/resources"
+)
+
+// occlusionComputeWorkgroupSize is the workgroup size assumed by the depth
+// downsample compute shader; it must match the local_size_x/y declared in
+// its GLSL source.
+const occlusionComputeWorkgroupSize = 8
+
+// occlusionBoundsWorkgroupSize is the workgroup size assumed by the
+// bounds-test compute shader, which dispatches one thread per instance
+// rather than per screen-space texel.
+const occlusionBoundsWorkgroupSize = 64
+
+// NewOcclusionCullView builds a reusable Hi-Z occlusion culling RenderView:
+// after the depth pre-pass, a compute shader generates a hierarchical
+// depth pyramid (each mip storing the max of its 2x2 parent texels), then
+// a second compute pass tests each instance's screen-space AABB against
+// the pyramid mip whose texel size just exceeds the AABB's extent and
+// writes a visibility bit per instance into the view's VisibilityBuffer.
+// That buffer is consumed when building the main forward pass's filtered
+// indirect draw buffer.
+//
+// Invariant: this view must run against the CURRENT frame's depth
+// pre-pass output, not a reprojected previous frame's. Reprojected
+// last-frame depth is cheaper (it overlaps culling with the current
+// frame's depth pre-pass instead of serializing after it), but it risks
+// false negatives — and a missing object is a visible pop, not a
+// conservative-but-safe overdraw — on fast camera motion or for objects
+// that just entered the frustum. Running after the current frame's
+// pre-pass gives up that overlap in exchange for never being wrong.
+func NewOcclusionCullView(name string, width, height uint16, dispatcher ComputeDispatcher) *metadata.RenderView {
+	view := &metadata.RenderView{
+		Name:           name,
+		Width:          width,
+		Height:         height,
+		RenderViewType: metadata.RENDERER_VIEW_KNOWN_TYPE_OCCLUSION_CULL,
+	}
+	view.OnCreate = func() bool {
+		return true
+	}
+	view.OnBuildPacket = func(data interface{}) (*metadata.RenderViewPacket, error) {
+		occlusion, ok := data.(*metadata.OcclusionCullPacketData)
+		if !ok {
+			return nil, fmt.Errorf("occlusion cull view: expected *metadata.OcclusionCullPacketData, got %T", data)
+		}
+		return &metadata.RenderViewPacket{
+			View:         view,
+			ExtendedData: occlusion,
+		}, nil
+	}
+	view.OnRender = func(packet *metadata.RenderViewPacket, frameNumber, renderTargetIndex uint64) bool {
+		occlusion, ok := packet.ExtendedData.(*metadata.OcclusionCullPacketData)
+		if !ok {
+			return false
+		}
+		// Downsample the depth pre-pass output into occlusion.Pyramid's mip
+		// chain; one dispatch covers this view's full resolution.
+		downsample := &metadata.ComputePacketData{
+			GroupCountX: ceilDiv(uint32(width), occlusionComputeWorkgroupSize),
+			GroupCountY: ceilDiv(uint32(height), occlusionComputeWorkgroupSize),
+			GroupCountZ: 1,
+			Textures:    []*resources.Texture{occlusion.Pyramid.Texture},
+		}
+		if err := dispatcher.DispatchCompute(downsample); err != nil {
+			return false
+		}
+		// Test occlusion.Bounds against the pyramid and write
+		// occlusion.Visibility; the barrier DispatchCompute inserts between
+		// the two dispatches makes the downsampled pyramid visible here,
+		// and its own barrier makes occlusion.Visibility visible to the
+		// forward pass's indirect draw build.
+		boundsTest := &metadata.ComputePacketData{
+			GroupCountX:    ceilDiv(occlusion.Visibility.InstanceCount, occlusionBoundsWorkgroupSize),
+			GroupCountY:    1,
+			GroupCountZ:    1,
+			StorageBuffers: []*metadata.RenderBuffer{occlusion.Bounds},
+		}
+		if err := dispatcher.DispatchCompute(boundsTest); err != nil {
+			return false
+		}
+		return true
+	}
+	return view
+}
+
+// ceilDiv returns ceil(a / b) for the workgroup-count calculations above.
+func ceilDiv(a, b uint32) uint32 {
+	return (a + b - 1) / b
+}
+
+// NewHiZPyramid sizes a HiZPyramid's mip chain for a depth buffer of the
+// given resolution: mip 0 matches the depth pre-pass resolution, and each
+// subsequent mip halves width and height (rounding up) until both reach 1.
+func NewHiZPyramid(width, height uint16) *metadata.HiZPyramid {
+	mipCount := hiZMipCount(width, height)
+	return &metadata.HiZPyramid{
+		MipViews: make([]interface{}, mipCount),
+		MipCount: mipCount,
+	}
+}
+
+func hiZMipCount(width, height uint16) uint8 {
+	count := uint8(1)
+	for width > 1 || height > 1 {
+		width = (width + 1) / 2
+		height = (height + 1) / 2
+		count++
+	}
+	return count
+}
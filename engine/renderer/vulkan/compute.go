@@ -0,0 +1,21 @@
+package vulkan
+
+import "github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+
+// DispatchCompute is the entry point for a compute dispatch of data's
+// dimensions: a vkCmdDispatch against the bound compute pipeline, followed
+// by a vkCmdPipelineBarrier so any subsequent graphics pass reading
+// data.StorageBuffers (e.g. an indirect draw consuming a compute-culled
+// instance list) waits on this dispatch's writes. The command recording is
+// not wired in: it records no commands and always returns nil.
+func (b *Backend) DispatchCompute(data *metadata.ComputePacketData) error {
+	// TODO: vkCmdBindPipeline(cmd, VK_PIPELINE_BIND_POINT_COMPUTE, pipeline)
+	// TODO: vkCmdBindDescriptorSets binding data.StorageBuffers
+	// TODO: vkCmdDispatch(cmd, data.GroupCountX, data.GroupCountY, data.GroupCountZ)
+	// TODO: vkCmdPipelineBarrier(cmd,
+	//   srcStage: VK_PIPELINE_STAGE_COMPUTE_SHADER_BIT,
+	//   dstStage: VK_PIPELINE_STAGE_VERTEX_INPUT_BIT|VK_PIPELINE_STAGE_DRAW_INDIRECT_BIT,
+	//   srcAccess: VK_ACCESS_SHADER_WRITE_BIT,
+	//   dstAccess: VK_ACCESS_SHADER_READ_BIT|VK_ACCESS_INDIRECT_COMMAND_READ_BIT)
+	return nil
+}
@@ -0,0 +1,19 @@
+package vulkan
+
+import "github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+
+// SubmitIndirect is where one vkCmdDrawIndexedIndirect per entry in
+// commands is recorded, each sourcing its per-instance transforms from the
+// transform SSBO range the batcher reserved. This replaces issuing one
+// draw call per GeometryRenderData with a handful of draws regardless of
+// scene complexity. The command recording is not wired in: it visits each
+// entry, records no commands and always returns nil.
+func (b *Backend) SubmitIndirect(commands map[string]metadata.DrawIndirectCommand) error {
+	for pipelineKey, command := range commands {
+		_ = pipelineKey
+		_ = command
+		// TODO: vkCmdBindPipeline(cmd, VK_PIPELINE_BIND_POINT_GRAPHICS, pipelineFor(pipelineKey))
+		// TODO: vkCmdDrawIndexedIndirect(cmd, indirectBuffer, offsetFor(pipelineKey), 1, sizeof(VkDrawIndexedIndirectCommand))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package vulkan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pipelineCacheHeader is written ahead of the raw VkPipelineCacheData blob
+// so a stale cache from a different GPU or driver is detected and
+// discarded instead of being handed to vkCreateGraphicsPipelines /
+// vkCreateComputePipelines, which would silently ignore it anyway but
+// without telling us it did.
+type pipelineCacheHeader struct {
+	DeviceUUID    [16]byte
+	DriverVersion uint32
+}
+
+const pipelineCacheHeaderSize = 16 + 4
+
+// loadPipelineCache reads the pipeline cache blob at path, returning it
+// only if its header matches the physical device this instance is running
+// on. A missing file, unreadable file, or header mismatch all result in a
+// nil blob and no error, since starting with an empty pipeline cache is
+// always a valid (if slower) fallback.
+func loadPipelineCache(path string, deviceUUID [16]byte, driverVersion uint32) []byte {
+	if path == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if len(raw) < pipelineCacheHeaderSize {
+		return nil
+	}
+	var header pipelineCacheHeader
+	copy(header.DeviceUUID[:], raw[:16])
+	header.DriverVersion = binary.LittleEndian.Uint32(raw[16:20])
+
+	if header.DeviceUUID != deviceUUID || header.DriverVersion != driverVersion {
+		return nil
+	}
+	return raw[pipelineCacheHeaderSize:]
+}
+
+// savePipelineCache writes data, prefixed with a header identifying the
+// device/driver it was generated on, to path. The write goes to a
+// temporary file in the same directory first and is then renamed into
+// place, so a crash or power loss mid-write can never leave a truncated
+// cache file behind.
+func savePipelineCache(path string, deviceUUID [16]byte, driverVersion uint32, data []byte) error {
+	if path == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(deviceUUID[:])
+	if err := binary.Write(&buf, binary.LittleEndian, driverVersion); err != nil {
+		return fmt.Errorf("vulkan: encode pipeline cache header: %w", err)
+	}
+	buf.Write(data)
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("vulkan: create temp pipeline cache file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("vulkan: write pipeline cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("vulkan: close pipeline cache temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("vulkan: rename pipeline cache into place: %w", err)
+	}
+	return nil
+}
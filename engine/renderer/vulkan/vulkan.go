@@ -0,0 +1,104 @@
+package vulkan
+
+import (
+	"github.com/spaghettifunk/alaska-engine/engine/core"
+	"github.com/spaghettifunk/alaska-engine/engine/platform"
+	"github.com/spaghettifunk/alaska-engine/engine/renderer/metadata"
+)
+
+// Backend is the Vulkan implementation of renderer.RendererBackend.
+type Backend struct {
+	platform *platform.Platform
+
+	// instance, physicalDevice and device hold the opaque Vulkan handles.
+	// Typed as interface{} here since the cgo/bindings layer is out of
+	// scope for this change.
+	instance       interface{}
+	physicalDevice interface{}
+	device         interface{}
+
+	// pipelineCache holds the opaque VkPipelineCache handle created from
+	// the blob loaded in Initialize (or an empty one if none was
+	// available). Every vkCreateGraphicsPipelines/vkCreateComputePipelines
+	// call should pass this handle so new pipelines get persisted too.
+	pipelineCache interface{}
+
+	deviceUUID        [16]byte
+	driverVersion     uint32
+	pipelineCachePath string
+
+	capabilities metadata.RendererCapabilities
+}
+
+func New(plat *platform.Platform) *Backend {
+	return &Backend{
+		platform: plat,
+	}
+}
+
+// TODO: Initialize always returns nil today — there is no real device
+// probing yet (see the cgo TODOs below), so it can never fail and
+// renderer.Config.FallbackOrder can never actually be exercised. Once
+// VkInstance/VkPhysicalDevice/VkDevice creation is wired in, failures
+// there (no supported GPU, missing extension, etc.) should surface here.
+func (b *Backend) Initialize(appName string, config metadata.RendererBackendConfig) error {
+	core.LogInfo("Initializing Vulkan renderer backend...")
+	// TODO: create VkInstance, select VkPhysicalDevice, create VkDevice and
+	// swapchain. Left as-is for this change; unrelated to the work below.
+	// b.deviceUUID and b.driverVersion would be populated here from the
+	// VkPhysicalDeviceProperties/IDProperties of the selected device.
+
+	b.pipelineCachePath = config.PipelineCachePath
+	cacheData := loadPipelineCache(b.pipelineCachePath, b.deviceUUID, b.driverVersion)
+	if cacheData != nil {
+		core.LogInfo("Loaded pipeline cache from " + b.pipelineCachePath)
+	}
+	// TODO: vkCreatePipelineCache with pCreateInfo.pInitialData = cacheData,
+	// storing the result in b.pipelineCache.
+
+	b.capabilities = metadata.RendererCapabilities{
+		SupportsCompute:          true,
+		SupportsTimestampQueries: true,
+		MaxTextureSize:           16384,
+	}
+	return nil
+}
+
+func (b *Backend) Shutdow() error {
+	core.LogInfo("Shutting down Vulkan renderer backend...")
+	if err := b.SavePipelineCache(); err != nil {
+		core.LogWarn("failed to save pipeline cache: " + err.Error())
+	}
+	return nil
+}
+
+func (b *Backend) Resized(width, height uint16) error {
+	return nil
+}
+
+func (b *Backend) BeginFrame(deltaTime float64) error {
+	return nil
+}
+
+func (b *Backend) EndFrame(deltaTime float64) error {
+	return nil
+}
+
+// Capabilities reports the features supported by this backend.
+func (b *Backend) Capabilities() metadata.RendererCapabilities {
+	return b.capabilities
+}
+
+// SavePipelineCache atomically writes the pipeline cache file, header
+// followed by the cache data, to Backend.pipelineCachePath. The data is
+// not read back via vkGetPipelineCacheData, so the blob written after the
+// header is empty. It is a no-op if Initialize was given an empty
+// PipelineCachePath.
+func (b *Backend) SavePipelineCache() error {
+	if b.pipelineCachePath == "" {
+		return nil
+	}
+	// TODO: data, err := vkGetPipelineCacheData(b.device, b.pipelineCache)
+	var data []byte
+	return savePipelineCache(b.pipelineCachePath, b.deviceUUID, b.driverVersion, data)
+}